@@ -0,0 +1,39 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+// EventKind identifies the kind of an Event sent on the simulation's event hub. It is a
+// string so that it travels as-is across the simulationpb gRPC transport and can be used
+// directly as an EventFilter.Kinds entry.
+type EventKind string
+
+const (
+	// ClockEvent is sent every time the simulation clock advances.
+	ClockEvent EventKind = "ClockEvent"
+	// TimeFactorChangedEvent is sent whenever SetTimeFactor changes the simulation's time
+	// acceleration factor, so that UIs can update their clock displays.
+	TimeFactorChangedEvent EventKind = "TimeFactorChangedEvent"
+)
+
+// Event is the payload sent to simulation event subscribers, whether they read from the
+// legacy EventChan or from an EventSource returned by Simulation.Subscribe.
+type Event struct {
+	Kind   EventKind
+	Object interface{}
+}
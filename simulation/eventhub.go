@@ -0,0 +1,274 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// subscriberQueueSize bounds how many pending events a single subscriber can hold before the
+// hub starts dropping events for it. A slow consumer must never be able to back-pressure the
+// goroutine that drives the simulation.
+const subscriberQueueSize = 1024
+
+// ErrSubscriptionClosed is returned by EventSource.Next once the subscription has been closed,
+// either explicitly via Close or because the Hub itself was closed.
+var ErrSubscriptionClosed = errors.New("simulation: event subscription closed")
+
+// EventFilter selects which events a subscriber is interested in. A zero-value EventFilter
+// matches every event. When Kinds is non-empty, only events of one of those kinds are
+// delivered; when ObjectIDs is non-empty, only events whose payload carries one of those IDs
+// (a train service code, a route number, a track item ID, ...) are delivered. The two
+// constraints combine with a logical AND.
+type EventFilter struct {
+	Kinds     []EventKind
+	ObjectIDs []string
+}
+
+// matches reports whether evt should be delivered to a subscriber with this filter.
+func (f EventFilter) matches(evt *Event) bool {
+	if len(f.Kinds) > 0 {
+		found := false
+		for _, k := range f.Kinds {
+			if k == evt.Kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.ObjectIDs) > 0 {
+		id := objectID(evt.Object)
+		if id == "" {
+			return false
+		}
+		found := false
+		for _, want := range f.ObjectIDs {
+			if want == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// objectID extracts a stable identifier from an event's payload for ObjectIDs filtering, e.g.
+// a train's service code or a route's number. Payloads that carry no identifier of their own
+// (a plain clock tick, for instance) never match an ObjectIDs filter.
+func objectID(object interface{}) string {
+	switch o := object.(type) {
+	case *Train:
+		return o.ServiceCode
+	case *Route:
+		return strconv.Itoa(o.ID())
+	case TrackItem:
+		return strconv.Itoa(o.ID())
+	default:
+		return ""
+	}
+}
+
+// EventSource yields the events matching the EventFilter a subscriber registered with. It is
+// returned by Hub.Subscribe and Simulation.Subscribe.
+type EventSource interface {
+	// Next blocks until a matching event is available, the subscription is closed, or the
+	// owning Hub is closed. It returns ErrSubscriptionClosed in the latter two cases.
+	Next() (*Event, error)
+	// Close unregisters the subscription. Any goroutine blocked in Next returns
+	// ErrSubscriptionClosed.
+	Close() error
+	// Overflowed returns the number of events that were dropped for this subscriber because
+	// its queue was full, i.e. it was not consuming Next fast enough.
+	Overflowed() uint64
+}
+
+// Hub fans out simulation events to any number of subscribers, each with its own bounded
+// queue and EventFilter. It is modeled after Cloud Foundry BBS's events package: rather than
+// letting one slow consumer block the goroutine that drives the simulation, the hub drops
+// events for that subscriber and reports the overflow on its EventSource instead of
+// back-pressuring the publisher.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[*subscription]struct{}
+	closed      bool
+	nextID      uint64
+}
+
+// NewHub creates an empty event hub.
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[*subscription]struct{})}
+}
+
+// Subscribe registers a new subscriber matching filter and returns its EventSource.
+func (h *Hub) Subscribe(filter EventFilter) (EventSource, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrSubscriptionClosed
+	}
+	h.nextID++
+	sub := &subscription{
+		hub:    h,
+		id:     h.nextID,
+		filter: filter,
+		events: make(chan *Event, subscriberQueueSize),
+		done:   make(chan struct{}),
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Publish delivers evt to every subscriber whose filter matches it. It never blocks: if a
+// subscriber's queue is full, the event is dropped for that subscriber and its overflow
+// counter is incremented instead. The drop warning, if any, is logged after h.mu is released
+// and collapsed into a single line for the whole call, so that a burst of slow subscribers
+// can't serialize publishing behind the logger or flood the log with one line per drop.
+func (h *Hub) Publish(evt *Event) {
+	h.mu.Lock()
+	dropped := 0
+	for sub := range h.subscribers {
+		if !sub.filter.matches(evt) {
+			continue
+		}
+		select {
+		case sub.events <- evt:
+		default:
+			atomic.AddUint64(&sub.overflowed, 1)
+			dropped++
+		}
+	}
+	h.mu.Unlock()
+
+	if dropped > 0 {
+		logger.Warn("event subscriber queue full, dropping event", "kind", evt.Kind, "subscribers", dropped)
+	}
+}
+
+// Close shuts down the hub and every subscription registered on it. Subsequent calls to
+// Subscribe return ErrSubscriptionClosed.
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	h.closed = true
+	for sub := range h.subscribers {
+		sub.markClosed()
+	}
+	h.subscribers = nil
+	return nil
+}
+
+// SubscriberStats summarizes one subscriber's queue, for monitoring purposes (see the
+// simulation/metrics subpackage).
+type SubscriberStats struct {
+	// Backlog is the number of events currently queued but not yet consumed via Next.
+	Backlog int
+	// Overflowed is the number of events dropped for this subscriber because its queue was
+	// full.
+	Overflowed uint64
+}
+
+// Stats returns a snapshot of queue backlog and overflow count for every current subscriber,
+// keyed by a stable per-subscriber label. Subscriptions carry no client-supplied name, so the
+// label is derived from a per-hub sequence number assigned at Subscribe time: unlike a pointer
+// address, it is never reused once a subscription is closed, so a scrape can't attribute a
+// dropped subscriber's counters to a newer, unrelated one.
+func (h *Hub) Stats() map[string]SubscriberStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stats := make(map[string]SubscriberStats, len(h.subscribers))
+	for sub := range h.subscribers {
+		stats[strconv.FormatUint(sub.id, 10)] = SubscriberStats{
+			Backlog:    len(sub.events),
+			Overflowed: sub.Overflowed(),
+		}
+	}
+	return stats
+}
+
+// unsubscribe removes sub from the hub's subscriber set. Called by subscription.Close.
+func (h *Hub) unsubscribe(sub *subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers, sub)
+}
+
+// subscription is the Hub's EventSource implementation.
+type subscription struct {
+	hub *Hub
+	// id is a per-hub sequence number assigned at Subscribe time, used as this subscription's
+	// stable label in Hub.Stats.
+	id         uint64
+	filter     EventFilter
+	events     chan *Event
+	done       chan struct{}
+	closeOnce  sync.Once
+	overflowed uint64
+}
+
+// Next implements EventSource.
+func (s *subscription) Next() (*Event, error) {
+	select {
+	case evt := <-s.events:
+		return evt, nil
+	case <-s.done:
+		return nil, ErrSubscriptionClosed
+	}
+}
+
+// Close implements EventSource.
+func (s *subscription) Close() error {
+	s.hub.unsubscribe(s)
+	s.markClosed()
+	return nil
+}
+
+// markClosed closes the done channel at most once, waking up any goroutine blocked in Next.
+func (s *subscription) markClosed() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// Overflowed implements EventSource.
+func (s *subscription) Overflowed() uint64 {
+	return atomic.LoadUint64(&s.overflowed)
+}
+
+// forwardToChan relays every event from source onto ch, for backwards compatibility with
+// clients that still read from Simulation.EventChan directly instead of calling Subscribe.
+// It returns once source is closed.
+func forwardToChan(source EventSource, ch chan *Event) {
+	for {
+		evt, err := source.Next()
+		if err != nil {
+			return
+		}
+		ch <- evt
+	}
+}
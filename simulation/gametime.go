@@ -0,0 +1,63 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// GameTime is the simulation's internal clock: a plain, by-value wrapper around time.Time. It
+// carries no synchronization of its own - it is copied by value in Add, MarshalJSON and every
+// snapshot/event that carries a timestamp, so a lock embedded in it would never actually
+// protect anything. Concurrent access to Simulation.Options.CurrentTime is instead guarded by
+// Simulation.clockMu; see Simulation.currentTime and Simulation.setCurrentTime.
+type GameTime struct {
+	t time.Time
+}
+
+// NewGameTime creates a GameTime set to t.
+func NewGameTime(t time.Time) GameTime {
+	return GameTime{t: t}
+}
+
+// Add returns a new GameTime step further ahead.
+func (gt GameTime) Add(step time.Duration) GameTime {
+	return GameTime{t: gt.t.Add(step)}
+}
+
+// Before reports whether gt is strictly earlier than u.
+func (gt GameTime) Before(u time.Time) bool {
+	return gt.t.Before(u)
+}
+
+// Time returns the underlying time.Time value.
+func (gt GameTime) Time() time.Time {
+	return gt.t
+}
+
+// MarshalJSON encodes the GameTime as its underlying time, for the scenario file format.
+func (gt GameTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(gt.t)
+}
+
+// UnmarshalJSON decodes a GameTime from a plain JSON time, for the scenario file format.
+func (gt *GameTime) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &gt.t)
+}
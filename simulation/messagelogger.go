@@ -0,0 +1,100 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+import "time"
+
+// msgKind categorizes a message logged through MessageLogger.
+type msgKind int
+
+const (
+	softwareMsg msgKind = iota
+	playerWarningMsg
+	signalPassedAtDangerMsg
+	routeConflictMsg
+)
+
+// message is a single entry in a MessageLogger's history.
+type message struct {
+	Text string
+	Kind msgKind
+	Time time.Time
+}
+
+// MessageLogger records textual messages emitted by the simulation - software diagnostics,
+// player warnings, signalling incidents - for display to the user and, via Simulation.
+// Snapshot, for later replay.
+type MessageLogger struct {
+	sim      *Simulation
+	messages []message
+
+	signalsPassedAtDanger int
+	routeConflicts        int
+}
+
+func (l *MessageLogger) setSimulation(sim *Simulation) { l.sim = sim }
+
+// addMessage appends a message of the given kind to the log, bumping the relevant incident
+// counter so it can be scraped by the metrics subpackage.
+func (l *MessageLogger) addMessage(text string, kind msgKind) {
+	l.messages = append(l.messages, message{Text: text, Kind: kind, Time: l.currentTime()})
+	switch kind {
+	case signalPassedAtDangerMsg:
+		l.signalsPassedAtDanger++
+	case routeConflictMsg:
+		l.routeConflicts++
+	}
+}
+
+func (l *MessageLogger) currentTime() time.Time {
+	if l.sim == nil {
+		return time.Time{}
+	}
+	return l.sim.currentTime().Time()
+}
+
+// history returns a copy of the logger's message history, for Simulation.Snapshot.
+func (l *MessageLogger) history() []message {
+	h := make([]message, len(l.messages))
+	copy(h, l.messages)
+	return h
+}
+
+// restoreHistory replaces the logger's message history, for Simulation.LoadSnapshot. Incident
+// counters are recomputed from the restored history so they stay consistent with it.
+func (l *MessageLogger) restoreHistory(messages []message) {
+	l.messages = messages
+	l.signalsPassedAtDanger = 0
+	l.routeConflicts = 0
+	for _, m := range messages {
+		switch m.Kind {
+		case signalPassedAtDangerMsg:
+			l.signalsPassedAtDanger++
+		case routeConflictMsg:
+			l.routeConflicts++
+		}
+	}
+}
+
+// SignalsPassedAtDangerCount returns the number of signal-passed-at-danger incidents logged
+// so far.
+func (l *MessageLogger) SignalsPassedAtDangerCount() int { return l.signalsPassedAtDanger }
+
+// RouteConflictCount returns the number of route conflict rejections logged so far.
+func (l *MessageLogger) RouteConflictCount() int { return l.routeConflicts }
@@ -0,0 +1,129 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+// Package metrics registers Prometheus collectors reading from a running simulation, so that
+// operators can scrape long-running training servers and alert on stuck simulations (ticker
+// skew, subscriber overflow).
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Source is the subset of simulation.Simulation that Collector reads from. simulation.
+// Simulation satisfies it structurally; this package does not import package simulation so as
+// not to create an import cycle with Simulation.MetricsCollector.
+type Source interface {
+	TrainStatusCounts() map[string]int
+	ReservedRouteCount() int
+	EventsSent() uint64
+	SubscriberBacklog() map[string]int
+	SubscriberOverflow() uint64
+	SignalsPassedAtDangerCount() int
+	RouteConflictCount() int
+}
+
+// Collector implements prometheus.Collector for a Source. Most of it is stateless between
+// scrapes: every Collect call pulls a fresh snapshot of source. The tick duration metric is the
+// exception - it is a Histogram, fed by Observe once per tick, so that it keeps the full
+// distribution of tick durations rather than only the most recent one, which is what an
+// operator needs to alert on a simulation whose ticks are skewing long under load.
+type Collector struct {
+	source Source
+
+	trainsByStatus    *prometheus.Desc
+	reservedRoutes    *prometheus.Desc
+	eventsSent        *prometheus.Desc
+	eventsDropped     *prometheus.Desc
+	subscriberBacklog *prometheus.Desc
+	tickDuration      prometheus.Histogram
+	signalsAtDanger   *prometheus.Desc
+	routeConflicts    *prometheus.Desc
+}
+
+// NewCollector creates a Collector reading from source. Register the result with a
+// prometheus.Registry, typically via prometheus.MustRegister(sim.MetricsCollector()).
+func NewCollector(source Source) *Collector {
+	return &Collector{
+		source: source,
+		trainsByStatus: prometheus.NewDesc(
+			"ts2_sim_trains", "Number of active trains by status.", []string{"status"}, nil),
+		reservedRoutes: prometheus.NewDesc(
+			"ts2_sim_reserved_routes", "Number of currently reserved routes.", nil, nil),
+		eventsSent: prometheus.NewDesc(
+			"ts2_sim_events_sent_total", "Number of events published on the event hub.", nil, nil),
+		eventsDropped: prometheus.NewDesc(
+			"ts2_sim_events_dropped_total",
+			"Number of events dropped because a subscriber's queue was full.", nil, nil),
+		subscriberBacklog: prometheus.NewDesc(
+			"ts2_sim_event_subscriber_backlog",
+			"Number of queued-but-unread events for an event hub subscriber.",
+			[]string{"subscriber"}, nil),
+		tickDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "ts2_sim_tick_duration_seconds",
+			Help:    "Real time spent in each increaseTime+event dispatch cycle.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		signalsAtDanger: prometheus.NewDesc(
+			"ts2_sim_signals_passed_at_danger_total",
+			"Number of signal-passed-at-danger incidents logged.", nil, nil),
+		routeConflicts: prometheus.NewDesc(
+			"ts2_sim_route_conflicts_total",
+			"Number of route conflict rejections logged.", nil, nil),
+	}
+}
+
+// Observe records the real time spent processing one clock tick (increaseTime plus event
+// dispatch) in the tick duration histogram. Called once per tick, whether driven by the clock
+// ticker or by Simulation.Step.
+func (c *Collector) Observe(d time.Duration) {
+	c.tickDuration.Observe(d.Seconds())
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.trainsByStatus
+	ch <- c.reservedRoutes
+	ch <- c.eventsSent
+	ch <- c.eventsDropped
+	ch <- c.subscriberBacklog
+	c.tickDuration.Describe(ch)
+	ch <- c.signalsAtDanger
+	ch <- c.routeConflicts
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for status, n := range c.source.TrainStatusCounts() {
+		ch <- prometheus.MustNewConstMetric(c.trainsByStatus, prometheus.GaugeValue, float64(n), status)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.reservedRoutes, prometheus.GaugeValue, float64(c.source.ReservedRouteCount()))
+	ch <- prometheus.MustNewConstMetric(c.eventsSent, prometheus.CounterValue, float64(c.source.EventsSent()))
+	ch <- prometheus.MustNewConstMetric(c.eventsDropped, prometheus.CounterValue, float64(c.source.SubscriberOverflow()))
+
+	for subscriber, backlog := range c.source.SubscriberBacklog() {
+		ch <- prometheus.MustNewConstMetric(c.subscriberBacklog, prometheus.GaugeValue, float64(backlog), subscriber)
+	}
+
+	c.tickDuration.Collect(ch)
+	ch <- prometheus.MustNewConstMetric(c.signalsAtDanger, prometheus.CounterValue, float64(c.source.SignalsPassedAtDangerCount()))
+	ch <- prometheus.MustNewConstMetric(c.routeConflicts, prometheus.CounterValue, float64(c.source.RouteConflictCount()))
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+// options holds the simulation-wide settings and mutable run state that are not tied to a
+// specific TrackItem, Train, Route or Service. It is decoded directly from the scenario file
+// as part of Simulation.UnmarshalJSON.
+type options struct {
+	CurrentTime GameTime `json:"currentTime"`
+	// TimeFactor is the speed at which the simulation clock advances relative to real time.
+	// A zero value is treated as 1 (real time) by Simulation.Initialize. See
+	// Simulation.SetTimeFactor.
+	TimeFactor float64 `json:"timeFactor"`
+	// Seed seeds Simulation.Rand, so that a scenario's non-deterministic decisions (train
+	// departure jitter, service re-assignment, ...) replay identically across runs with the
+	// same seed. See Simulation.Step and Simulation.RunUntil.
+	Seed int64 `json:"seed"`
+}
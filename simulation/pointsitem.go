@@ -0,0 +1,65 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+// PointsPosition represents the physical position of a PointsItem's switch blade.
+type PointsPosition int
+
+const (
+	// NormalPosition is the points item's default, unreversed position.
+	NormalPosition PointsPosition = iota
+	// ReversePosition is the points item's reversed position.
+	ReversePosition
+)
+
+// PointsItem is a TrackItem representing a set of points (a switch) with a normal and a
+// reverse route.
+type PointsItem struct {
+	id int
+
+	sim      *Simulation
+	position PointsPosition
+
+	nextItem     TrackItem
+	previousItem TrackItem
+	reverseItem  TrackItem
+}
+
+func (pi *PointsItem) Type() trackItemType          { return pointsItem }
+func (pi *PointsItem) ID() int                      { return pi.id }
+func (pi *PointsItem) setID(id int)                 { pi.id = id }
+func (pi *PointsItem) setSimulation(sim *Simulation) { pi.sim = sim }
+func (pi *PointsItem) NextItem() TrackItem           { return pi.nextItem }
+func (pi *PointsItem) PreviousItem() TrackItem       { return pi.previousItem }
+
+// ReverseItem returns the TrackItem connected to this points item's reverse branch.
+func (pi *PointsItem) ReverseItem() TrackItem { return pi.reverseItem }
+
+func (pi *PointsItem) Origin() Point { return Point{} }
+func (pi *PointsItem) End() Point    { return Point{} }
+
+// Reverse returns the coordinates of the points item's reverse connection point, used when
+// reporting a linking error.
+func (pi *PointsItem) Reverse() Point { return Point{} }
+
+// Position returns the points item's current switch position.
+func (pi *PointsItem) Position() PointsPosition { return pi.position }
+
+// SetPosition moves the points item's switch to position.
+func (pi *PointsItem) SetPosition(position PointsPosition) { pi.position = position }
@@ -0,0 +1,72 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+import "fmt"
+
+// routeState represents the reservation state of a Route.
+type routeState int
+
+const (
+	routeFree routeState = iota
+	routeReserved
+	routeActivated
+	routeDestroying
+)
+
+// Route is a path a train can be routed along, from an origin signal to a destination
+// signal, through zero or more points items.
+type Route struct {
+	id int
+
+	sim    *Simulation
+	active bool
+	state  routeState
+}
+
+func (r *Route) setSimulation(sim *Simulation) { r.sim = sim }
+
+// initialize prepares the route after the scenario file has been fully decoded.
+func (r *Route) initialize() {}
+
+// ID returns the route's number, as used for its key in Simulation.Routes.
+func (r *Route) ID() int { return r.id }
+
+// IsActive reports whether the route is currently reserved for a train.
+func (r *Route) IsActive() bool { return r.active }
+
+// State returns the route's current reservation state.
+func (r *Route) State() routeState { return r.state }
+
+// setState restores the route's reservation state, as done by Simulation.LoadSnapshot.
+func (r *Route) setState(state routeState, active bool) {
+	r.state = state
+	r.active = active
+}
+
+// Activate reserves the route, making it unavailable to other trains until it is released.
+// It fails if the route is already reserved by someone else.
+func (r *Route) Activate() error {
+	if r.active && r.state != routeActivated {
+		return fmt.Errorf("route %d is already reserved", r.id)
+	}
+	r.state = routeActivated
+	r.active = true
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+import "time"
+
+// ServiceLine is one scheduled stop of a Service.
+type ServiceLine struct {
+	ScheduledDepartureTime time.Time
+}
+
+// Service describes a timetabled run that a Train can be assigned to.
+type Service struct {
+	Code  string
+	Lines []ServiceLine
+	// NextServiceCodes lists the services a train finishing this one may continue as. When
+	// there is more than one candidate, Simulation.assignNextService picks between them using
+	// sim.Rand, so the choice is reproducible for a given Options.Seed.
+	NextServiceCodes []string
+
+	sim         *Simulation
+	currentLine int
+}
+
+func (s *Service) setSimulation(sim *Simulation) { s.sim = sim }
+
+// CurrentLine returns the index of the service's current line in Lines.
+func (s *Service) CurrentLine() int { return s.currentLine }
+
+// setCurrentLine restores the service's progress, as done by Simulation.LoadSnapshot.
+func (s *Service) setCurrentLine(n int) { s.currentLine = n }
+
+// assignNextService picks the Service a train should continue as once it finishes s, drawing
+// from sim.Rand when more than one candidate is configured so the choice is reproducible for
+// a given Options.Seed. It returns nil if s has no configured continuation.
+func (sim *Simulation) assignNextService(s *Service) *Service {
+	switch len(s.NextServiceCodes) {
+	case 0:
+		return nil
+	case 1:
+		return sim.Services[s.NextServiceCodes[0]]
+	default:
+		code := s.NextServiceCodes[sim.Rand.Intn(len(s.NextServiceCodes))]
+		return sim.Services[code]
+	}
+}
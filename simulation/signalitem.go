@@ -0,0 +1,52 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+// Aspect describes a signal's current displayed indication.
+type Aspect struct {
+	Name string
+}
+
+// SignalItem is a TrackItem representing a signal and the aspect it currently displays.
+type SignalItem struct {
+	id int
+
+	sim    *Simulation
+	aspect Aspect
+
+	nextItem     TrackItem
+	previousItem TrackItem
+}
+
+func (si *SignalItem) Type() trackItemType          { return signalItem }
+func (si *SignalItem) ID() int                      { return si.id }
+func (si *SignalItem) setID(id int)                 { si.id = id }
+func (si *SignalItem) setSimulation(sim *Simulation) { si.sim = sim }
+func (si *SignalItem) NextItem() TrackItem           { return si.nextItem }
+func (si *SignalItem) PreviousItem() TrackItem       { return si.previousItem }
+func (si *SignalItem) Origin() Point                 { return Point{} }
+func (si *SignalItem) End() Point                    { return Point{} }
+
+// Aspect returns the aspect the signal currently displays.
+func (si *SignalItem) Aspect() Aspect { return si.aspect }
+
+// setAspectByName sets the signal's aspect by its name, as restored from a snapshot.
+func (si *SignalItem) setAspectByName(name string) {
+	si.aspect = Aspect{Name: name}
+}
@@ -21,15 +21,26 @@ package simulation
 import (
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	log "gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/ts2/ts2-sim-server/simulation/metrics"
 )
 
-const timeStep = 500 * time.Millisecond
+const (
+	timeStep = 500 * time.Millisecond
+	// minTickPeriod is the shortest real-time interval the clock ticker is allowed to run at.
+	// Beyond this, a higher TimeFactor grows the per-tick game-time delta instead of shrinking
+	// the ticker period further, so that stepping stays smooth at high acceleration factors.
+	minTickPeriod = 50 * time.Millisecond
+)
 
 var logger log.Logger
 
@@ -49,10 +60,34 @@ type Simulation struct {
 	Services      map[string]*Service
 	Trains        []*Train
 	MessageLogger *MessageLogger
-	EventChan     chan *Event
+	// EventChan receives every event published on the simulation's event hub, for backwards
+	// compatibility with code written before Subscribe existed. Unlike Subscribe, it is backed
+	// by a single bounded queue (see forwardToChan and subscriberQueueSize): once that queue is
+	// full because EventChan isn't being drained fast enough, further events are dropped for
+	// it (with a warning logged by Hub.Publish) rather than blocking the simulation. Code that cannot tolerate dropped
+	// events should call Subscribe instead and watch EventSource.Overflowed.
+	EventChan chan *Event
+	// Rand is the single source of randomness for all non-deterministic decisions in the
+	// simulation (train delay jitter, service assignments, ...). It is seeded from
+	// Options.Seed in Initialize, so that re-running the same scenario with the same seed
+	// reproduces a byte-identical sequence of events.
+	Rand *rand.Rand
+
+	clockTicker        *time.Ticker
+	stopChan           chan bool
+	eventHub           *Hub
+	eventsSent         uint64
+	lastTickDurationNs int64
+	metricsCollector   *metrics.Collector
+
+	runMu   sync.Mutex
+	running bool
 
-	clockTicker *time.Ticker
-	stopChan    chan bool
+	// clockMu guards Options.CurrentTime and Options.TimeFactor, which the run goroutine
+	// reads and writes every tick while any other goroutine (an RPC handler, Snapshot, ...)
+	// may read or write them concurrently. See currentTime, setCurrentTime, timeFactor and
+	// setTimeFactor.
+	clockMu sync.Mutex
 }
 
 // UnmarshalJSON for the Simulation type
@@ -144,6 +179,7 @@ func (sim *Simulation) UnmarshalJSON(data []byte) error {
 		if errRoute != nil {
 			return fmt.Errorf("routeNum : `%s` is invalid", num)
 		}
+		route.id = routeNum
 		sim.Routes[routeNum] = route
 	}
 	sim.TrainTypes = rawSim.TrainTypes
@@ -182,15 +218,41 @@ func (sim *Simulation) Initialize() error {
 	sim.MessageLogger.addMessage("Simulation initializing", softwareMsg)
 	sim.EventChan = make(chan *Event)
 	sim.stopChan = make(chan bool)
+	sim.eventHub = NewHub()
+	allEvents, err := sim.eventHub.Subscribe(EventFilter{})
+	if err != nil {
+		return err
+	}
+	go forwardToChan(allEvents, sim.EventChan)
+	if sim.timeFactor() == 0 {
+		sim.setTimeFactor(1)
+	}
+	sim.Rand = rand.New(rand.NewSource(sim.Options.Seed))
+	sim.metricsCollector = metrics.NewCollector(sim)
 	return nil
 }
 
+// Subscribe returns an EventSource yielding the events matching filter. Unlike reading from
+// EventChan directly, each subscriber gets its own bounded queue: a slow consumer has events
+// dropped for it rather than blocking the simulation's main loop.
+func (sim *Simulation) Subscribe(filter EventFilter) (EventSource, error) {
+	return sim.eventHub.Subscribe(filter)
+}
+
 // Start runs the main loop of the simulation by making the clock tick and process each object.
+// Calling Start on an already-running simulation is a no-op: it does not spawn a second run
+// loop.
 func (sim *Simulation) Start() {
 	if sim.stopChan == nil || sim.EventChan == nil {
 		panic("You must call Initialize before starting the simulation")
 	}
-	sim.clockTicker = time.NewTicker(timeStep)
+	sim.runMu.Lock()
+	defer sim.runMu.Unlock()
+	if sim.running {
+		return
+	}
+	sim.running = true
+	sim.clockTicker = time.NewTicker(sim.tickPeriod())
 	go sim.run()
 	logger.Info("Simulation started")
 }
@@ -204,30 +266,113 @@ func (sim *Simulation) run() {
 			logger.Info("Simulation paused")
 			return
 		case <-sim.clockTicker.C:
-			sim.increaseTime(timeStep)
-			sim.sendEvent(&Event{ClockEvent, sim.Options.CurrentTime})
+			start := time.Now()
+			sim.increaseTime(sim.gameStep())
+			sim.advanceTrains()
+			sim.sendEvent(&Event{ClockEvent, sim.currentTime()})
+			sim.recordTickDuration(time.Since(start))
 		}
 	}
 }
 
-// Pause holds the simulation by stopping the clock ticker. Call Start again to restart the simulation.
+// SetTimeFactor changes the speed at which the simulation clock advances relative to real time.
+// A factor greater than 1 makes the game clock run faster than real time (e.g. 4 or 10 for
+// driver training scenarios), while a factor between 0 and 1 slows it down. The change takes
+// effect on the next tick: the ticker is reset to the new period and a TimeFactorChangedEvent
+// is sent so that clients can update their clock displays accordingly.
+func (sim *Simulation) SetTimeFactor(f float64) {
+	sim.setTimeFactor(f)
+	if sim.clockTicker != nil {
+		sim.clockTicker.Reset(sim.tickPeriod())
+	}
+	sim.sendEvent(&Event{TimeFactorChangedEvent, f})
+}
+
+// tickPeriod returns the real-time interval between two clock ticks for the current
+// TimeFactor. It never goes below minTickPeriod; past that point gameStep grows the amount of
+// game time applied per tick instead, so that stepping remains smooth at high factors.
+func (sim *Simulation) tickPeriod() time.Duration {
+	f := sim.timeFactor()
+	if f <= 1 {
+		return timeStep
+	}
+	period := time.Duration(float64(timeStep) / f)
+	if period < minTickPeriod {
+		return minTickPeriod
+	}
+	return period
+}
+
+// gameStep returns the amount of game time to apply on the next tick, given the current
+// TimeFactor and the real-time tick period returned by tickPeriod.
+func (sim *Simulation) gameStep() time.Duration {
+	return time.Duration(float64(sim.tickPeriod()) * sim.timeFactor())
+}
+
+// Pause holds the simulation by stopping the clock ticker. Call Start again to restart the
+// simulation. Calling Pause when the simulation is not running is a no-op: it does not block
+// waiting for a run loop that isn't there to receive on stopChan.
 func (sim *Simulation) Pause() {
+	sim.runMu.Lock()
+	defer sim.runMu.Unlock()
+	if !sim.running {
+		return
+	}
+	sim.running = false
 	sim.stopChan <- true
 }
 
-// sendEvent sends the given event on the event channel to notify clients.
-// Sending is done asynchronously so as not to block.
+// sendEvent publishes the given event to every subscriber on the event hub, including the
+// subscribe-all EventChan. Publishing never blocks the caller on a slow consumer: see Hub.
 func (sim *Simulation) sendEvent(evt *Event) {
-	go func() { sim.EventChan <- evt }()
+	atomic.AddUint64(&sim.eventsSent, 1)
+	sim.eventHub.Publish(evt)
+}
+
+// recordTickDuration stores d as the most recently observed tick duration and feeds it into
+// the tick duration histogram exposed by MetricsCollector.
+func (sim *Simulation) recordTickDuration(d time.Duration) {
+	atomic.StoreInt64(&sim.lastTickDurationNs, int64(d))
+	sim.metricsCollector.Observe(d)
 }
 
 // increaseTime adds the step to the simulation time.
 func (sim *Simulation) increaseTime(step time.Duration) {
-	sim.Options.CurrentTime.Lock()
-	defer sim.Options.CurrentTime.Unlock()
+	sim.clockMu.Lock()
+	defer sim.clockMu.Unlock()
 	sim.Options.CurrentTime = sim.Options.CurrentTime.Add(step)
 }
 
+// currentTime returns a snapshot of the simulation clock. Safe to call from any goroutine.
+func (sim *Simulation) currentTime() GameTime {
+	sim.clockMu.Lock()
+	defer sim.clockMu.Unlock()
+	return sim.Options.CurrentTime
+}
+
+// setCurrentTime overwrites the simulation clock, as Simulation.LoadSnapshot does. Safe to
+// call from any goroutine.
+func (sim *Simulation) setCurrentTime(t GameTime) {
+	sim.clockMu.Lock()
+	defer sim.clockMu.Unlock()
+	sim.Options.CurrentTime = t
+}
+
+// timeFactor returns the current TimeFactor. Safe to call from any goroutine.
+func (sim *Simulation) timeFactor() float64 {
+	sim.clockMu.Lock()
+	defer sim.clockMu.Unlock()
+	return sim.Options.TimeFactor
+}
+
+// setTimeFactor overwrites TimeFactor, as SetTimeFactor and Initialize do. Safe to call from
+// any goroutine.
+func (sim *Simulation) setTimeFactor(f float64) {
+	sim.clockMu.Lock()
+	defer sim.clockMu.Unlock()
+	sim.Options.TimeFactor = f
+}
+
 // checks that all TrackItems are linked together.
 // Returns the first error met.
 func (sim *Simulation) checkTrackItemsLinks() error {
@@ -0,0 +1,236 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// snapshotVersion identifies the schema of the payload produced by Snapshot. LoadSnapshot
+// checks it so that future schema changes can migrate older payloads instead of failing to
+// decode them.
+const snapshotVersion = 1
+
+// trainSnapshot captures the mutable runtime state of a single Train.
+type trainSnapshot struct {
+	ServiceCode string
+	Position    Position
+	Speed       float64
+	Status      trainStatus
+}
+
+// routeSnapshot captures the reservation state of a single Route.
+type routeSnapshot struct {
+	ID     int
+	Active bool
+	State  routeState
+}
+
+// pointsSnapshot captures the current position of a single PointsItem.
+type pointsSnapshot struct {
+	ID       int
+	Position PointsPosition
+}
+
+// signalSnapshot captures the current aspect of a single SignalItem.
+type signalSnapshot struct {
+	ID     int
+	Aspect string
+}
+
+// serviceSnapshot captures the progress of a single Service through its lines.
+type serviceSnapshot struct {
+	Code        string
+	CurrentLine int
+}
+
+// snapshotPayload is the versioned, serializable representation of everything Snapshot and
+// LoadSnapshot round-trip. It deliberately excludes the immutable layout of the simulation
+// (TrackItems geometry, TrainTypes, SignalLib): that data is loaded once from the simulation
+// file and never changes at runtime, so re-sending it on every snapshot would be wasted space.
+type snapshotPayload struct {
+	Version  int
+	Time     GameTime
+	Trains   []trainSnapshot
+	Routes   []routeSnapshot
+	Points   []pointsSnapshot
+	Signals  []signalSnapshot
+	Services []serviceSnapshot
+	Messages []message
+}
+
+// Snapshot serializes the current mutable runtime state of the simulation - the game clock,
+// each Train's position/speed/status, active Route reservations, PointsItem positions,
+// SignalItem aspects, Service progress, and the MessageLogger history - into a payload that
+// can later be restored with LoadSnapshot. The immutable layout (TrackItems geometry,
+// TrainTypes, SignalLib) is not included, since it never changes once the simulation has
+// loaded.
+func (sim *Simulation) Snapshot() ([]byte, error) {
+	payload := snapshotPayload{
+		Version:  snapshotVersion,
+		Time:     sim.currentTime(),
+		Messages: sim.MessageLogger.history(),
+	}
+
+	for _, t := range sim.Trains {
+		payload.Trains = append(payload.Trains, trainSnapshot{
+			ServiceCode: t.ServiceCode,
+			Position:    t.Position(),
+			Speed:       t.Speed(),
+			Status:      t.Status(),
+		})
+	}
+
+	for _, num := range sortedRouteIDs(sim.Routes) {
+		r := sim.Routes[num]
+		payload.Routes = append(payload.Routes, routeSnapshot{
+			ID:     num,
+			Active: r.IsActive(),
+			State:  r.State(),
+		})
+	}
+
+	for _, id := range sortedTrackItemIDs(sim.TrackItems) {
+		switch item := sim.TrackItems[id].(type) {
+		case *PointsItem:
+			payload.Points = append(payload.Points, pointsSnapshot{ID: id, Position: item.Position()})
+		case *SignalItem:
+			payload.Signals = append(payload.Signals, signalSnapshot{ID: id, Aspect: item.Aspect().Name})
+		}
+	}
+
+	for _, code := range sortedServiceCodes(sim.Services) {
+		s := sim.Services[code]
+		payload.Services = append(payload.Services, serviceSnapshot{Code: code, CurrentLine: s.CurrentLine()})
+	}
+
+	data, err := json.Marshal(&payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode simulation snapshot: %s", err)
+	}
+	return data, nil
+}
+
+// LoadSnapshot restores the mutable runtime state of the simulation from a payload produced
+// by Snapshot, leaving the immutable layout untouched. It is safe to call while the
+// simulation is Paused, which makes it possible to fast-forward or rewind a running scenario
+// for debugging or replay purposes.
+func (sim *Simulation) LoadSnapshot(data []byte) error {
+	var payload snapshotPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("unable to decode simulation snapshot: %s", err)
+	}
+	if payload.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version: %d", payload.Version)
+	}
+
+	sim.setCurrentTime(payload.Time)
+
+	// Trains are matched by their index in sim.Trains, the order Snapshot writes them in:
+	// several trains may share a service code, and a train's service code changes at runtime.
+	if len(payload.Trains) != len(sim.Trains) {
+		return fmt.Errorf("snapshot holds %d trains, simulation has %d", len(payload.Trains), len(sim.Trains))
+	}
+	for i, ts := range payload.Trains {
+		t := sim.Trains[i]
+		t.ServiceCode = ts.ServiceCode
+		t.setPosition(ts.Position)
+		t.setSpeed(ts.Speed)
+		t.setStatus(ts.Status)
+	}
+
+	for _, rs := range payload.Routes {
+		r, ok := sim.Routes[rs.ID]
+		if !ok {
+			return fmt.Errorf("snapshot references unknown route %d", rs.ID)
+		}
+		r.setState(rs.State, rs.Active)
+	}
+
+	for _, ps := range payload.Points {
+		ti, ok := sim.TrackItems[ps.ID]
+		if !ok {
+			return fmt.Errorf("snapshot references unknown points item %d", ps.ID)
+		}
+		pi, ok := ti.(*PointsItem)
+		if !ok {
+			return fmt.Errorf("track item %d is not a PointsItem", ps.ID)
+		}
+		pi.SetPosition(ps.Position)
+	}
+
+	for _, ss := range payload.Signals {
+		ti, ok := sim.TrackItems[ss.ID]
+		if !ok {
+			return fmt.Errorf("snapshot references unknown signal item %d", ss.ID)
+		}
+		si, ok := ti.(*SignalItem)
+		if !ok {
+			return fmt.Errorf("track item %d is not a SignalItem", ss.ID)
+		}
+		si.setAspectByName(ss.Aspect)
+	}
+
+	for _, svcs := range payload.Services {
+		s, ok := sim.Services[svcs.Code]
+		if !ok {
+			return fmt.Errorf("snapshot references unknown service %s", svcs.Code)
+		}
+		s.setCurrentLine(svcs.CurrentLine)
+	}
+
+	sim.MessageLogger.restoreHistory(payload.Messages)
+
+	return nil
+}
+
+// sortedRouteIDs returns the keys of routes in ascending order, so that Snapshot produces the
+// same byte sequence across runs regardless of map iteration order.
+func sortedRouteIDs(routes map[int]*Route) []int {
+	ids := make([]int, 0, len(routes))
+	for id := range routes {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// sortedTrackItemIDs returns the keys of items in ascending order, so that Snapshot produces
+// the same byte sequence across runs regardless of map iteration order.
+func sortedTrackItemIDs(items map[int]TrackItem) []int {
+	ids := make([]int, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// sortedServiceCodes returns the keys of services in lexical order, so that Snapshot produces
+// the same byte sequence across runs regardless of map iteration order.
+func sortedServiceCodes(services map[string]*Service) []string {
+	codes := make([]string, 0, len(services))
+	for code := range services {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
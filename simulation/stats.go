@@ -0,0 +1,104 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The methods in this file expose read-only counters and snapshots of the simulation's
+// runtime health. The simulation/metrics subpackage reads them through the metrics.Source
+// interface, which Simulation satisfies structurally, so that package need not import this
+// one (this package imports it for MetricsCollector).
+
+// TrainStatusCounts returns the number of trains currently in each status, keyed by its
+// string representation.
+func (sim *Simulation) TrainStatusCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, t := range sim.Trains {
+		counts[t.Status().String()]++
+	}
+	return counts
+}
+
+// ReservedRouteCount returns the number of currently active (reserved) routes.
+func (sim *Simulation) ReservedRouteCount() int {
+	n := 0
+	for _, r := range sim.Routes {
+		if r.IsActive() {
+			n++
+		}
+	}
+	return n
+}
+
+// EventsSent returns the total number of events published on the event hub since Initialize.
+func (sim *Simulation) EventsSent() uint64 {
+	return atomic.LoadUint64(&sim.eventsSent)
+}
+
+// SubscriberBacklog returns the number of queued-but-unread events for each current event hub
+// subscriber, keyed by a stable per-subscriber label.
+func (sim *Simulation) SubscriberBacklog() map[string]int {
+	backlog := make(map[string]int)
+	for id, st := range sim.eventHub.Stats() {
+		backlog[id] = st.Backlog
+	}
+	return backlog
+}
+
+// SubscriberOverflow returns the number of events dropped because a subscriber's queue was
+// full, summed over the subscribers currently registered on the event hub. Events dropped for
+// a subscription that has since been closed are no longer counted.
+func (sim *Simulation) SubscriberOverflow() uint64 {
+	var dropped uint64
+	for _, st := range sim.eventHub.Stats() {
+		dropped += st.Overflowed
+	}
+	return dropped
+}
+
+// LastTickDuration returns the real time spent processing the most recent clock tick
+// (increaseTime plus event dispatch).
+func (sim *Simulation) LastTickDuration() time.Duration {
+	return time.Duration(atomic.LoadInt64(&sim.lastTickDurationNs))
+}
+
+// SignalsPassedAtDangerCount returns the number of signal-passed-at-danger incidents logged
+// so far.
+func (sim *Simulation) SignalsPassedAtDangerCount() int {
+	return sim.MessageLogger.SignalsPassedAtDangerCount()
+}
+
+// RouteConflictCount returns the number of route conflict rejections logged so far.
+func (sim *Simulation) RouteConflictCount() int {
+	return sim.MessageLogger.RouteConflictCount()
+}
+
+// MetricsCollector returns the prometheus.Collector reading from sim, for operators to register
+// with their own prometheus registry so that long-running training servers can be scraped and
+// alerted on (ticker skew, subscriber overflow, stuck simulations). It is the same Collector
+// instance that Initialize created and recordTickDuration feeds, so its tick duration histogram
+// reflects every tick since Initialize, not just those since this method was first called.
+func (sim *Simulation) MetricsCollector() prometheus.Collector {
+	return sim.metricsCollector
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+import "time"
+
+// maxDepartureJitter bounds the random delay Train.applyDepartureJitter may roll for a
+// train's departure, keeping seeded replays close to the scheduled timetable.
+const maxDepartureJitter = 2 * time.Minute
+
+// Step advances the simulation exactly n ticks synchronously: no ticker, no goroutine. Each
+// tick applies the same game-time delta that the running clock would (see gameStep), rolls
+// any pending train-departure jitter and service re-assignment through sim.Rand, and sends
+// the same ClockEvent. Combined with Rand being seeded from Options.Seed, this lets a
+// scenario be replayed headless in tests and CI and produce a byte-identical event trace.
+func (sim *Simulation) Step(n int) {
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		sim.increaseTime(sim.gameStep())
+		sim.advanceTrains()
+		sim.sendEvent(&Event{ClockEvent, sim.currentTime()})
+		sim.recordTickDuration(time.Since(start))
+	}
+}
+
+// advanceTrains rolls departure jitter and service re-assignment for every train, routing all
+// of it through sim.Rand so that a given Options.Seed always produces the same sequence of
+// decisions. Called on every tick, whether driven by the clock ticker or by Step.
+func (sim *Simulation) advanceTrains() {
+	for _, t := range sim.Trains {
+		t.applyDepartureJitter(sim, maxDepartureJitter)
+		t.maybeAssignNextService(sim)
+	}
+}
+
+// RunUntil steps the simulation synchronously, one tick at a time, until the in-game clock
+// reaches or passes t. Like Step, it never starts the clock ticker or a goroutine, so a test
+// or batch job can drive it deterministically from start to finish.
+func (sim *Simulation) RunUntil(t time.Time) {
+	for sim.currentTime().Before(t) {
+		sim.Step(1)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+// trackItemType identifies the concrete kind of a TrackItem.
+type trackItemType int
+
+const (
+	lineItem trackItemType = iota
+	invisibleLinkItem
+	endItem
+	platformItem
+	textItem
+	pointsItem
+	signalItem
+	place
+)
+
+// Point is a coordinate on the track layout, used when reporting linking errors.
+type Point struct {
+	X, Y float64
+}
+
+// TrackItem is implemented by every item that can appear in a simulation's track layout
+// (lines, points, signals, platforms, ...).
+type TrackItem interface {
+	Type() trackItemType
+	ID() int
+	setID(id int)
+	setSimulation(sim *Simulation)
+	NextItem() TrackItem
+	PreviousItem() TrackItem
+	Origin() Point
+	End() Point
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulation
+
+import "time"
+
+// trainStatus represents a train's current state in the scenario.
+type trainStatus int
+
+const (
+	trainNotDeparted trainStatus = iota
+	trainRunning
+	trainStopped
+	trainArrived
+)
+
+func (s trainStatus) String() string {
+	switch s {
+	case trainNotDeparted:
+		return "notDeparted"
+	case trainRunning:
+		return "running"
+	case trainStopped:
+		return "stopped"
+	case trainArrived:
+		return "arrived"
+	default:
+		return "unknown"
+	}
+}
+
+// Position locates a train along the track layout: the TrackItem it currently occupies and
+// how far along that item's length it has travelled.
+type Position struct {
+	TrackItemID int
+	Offset      float64
+}
+
+// Train represents a single train running a Service through the simulation.
+type Train struct {
+	ServiceCode string
+
+	sim           *Simulation
+	position      Position
+	speed         float64
+	status        trainStatus
+	delayJitter   time.Duration
+	jitterApplied bool
+}
+
+func (t *Train) setSimulation(sim *Simulation) { t.sim = sim }
+
+// Service returns the Service this train is currently running.
+func (t *Train) Service() *Service {
+	return t.sim.Services[t.ServiceCode]
+}
+
+// Position returns the train's current position on the track layout.
+func (t *Train) Position() Position { return t.position }
+
+// Speed returns the train's current speed, in the simulation's distance units per second.
+func (t *Train) Speed() float64 { return t.speed }
+
+// Status returns the train's current status.
+func (t *Train) Status() trainStatus { return t.status }
+
+func (t *Train) setPosition(p Position)  { t.position = p }
+func (t *Train) setSpeed(speed float64)  { t.speed = speed }
+func (t *Train) setStatus(s trainStatus) { t.status = s }
+
+// applyDepartureJitter rolls a random departure delay for a train that has not yet left its
+// origin, drawing from sim.Rand so the jitter is reproducible for a given Options.Seed. It is
+// a no-op once the jitter has already been rolled for this run or the train has departed.
+func (t *Train) applyDepartureJitter(sim *Simulation, max time.Duration) {
+	if t.jitterApplied || t.status != trainNotDeparted {
+		return
+	}
+	t.delayJitter = time.Duration(sim.Rand.Int63n(int64(max) + 1))
+	t.jitterApplied = true
+}
+
+// maybeAssignNextService reassigns an arrived train to continue as one of its current
+// service's NextServiceCodes, picking between multiple candidates via sim.Rand so the choice
+// is reproducible for a given Options.Seed. It is a no-op for a train that has not arrived or
+// whose service has no configured continuation.
+func (t *Train) maybeAssignNextService(sim *Simulation) {
+	if t.status != trainArrived {
+		return
+	}
+	next := sim.assignNextService(t.Service())
+	if next == nil {
+		return
+	}
+	t.ServiceCode = next.Code
+	t.status = trainNotDeparted
+	t.jitterApplied = false
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulationpb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ts2/ts2-sim-server/simulation"
+)
+
+// filterFromProto converts a FilterRequest into the simulation.EventFilter it describes.
+func filterFromProto(req *FilterRequest) simulation.EventFilter {
+	filter := simulation.EventFilter{ObjectIDs: req.ObjectIds}
+	for _, k := range req.Kinds {
+		filter.Kinds = append(filter.Kinds, simulation.EventKind(k))
+	}
+	return filter
+}
+
+// eventToProto converts a simulation.Event into the wire Event, JSON-encoding its payload so
+// that clients don't need a generated message type per event kind.
+func eventToProto(evt *simulation.Event) (*Event, error) {
+	payload, err := json.Marshal(evt.Object)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode event payload: %s", err)
+	}
+	return &Event{Kind: string(evt.Kind), Payload: payload}, nil
+}
+
+// pointsPositionFromProto converts the wire string position into the simulation's own
+// PointsPosition type.
+func pointsPositionFromProto(position string) simulation.PointsPosition {
+	switch position {
+	case "reverse":
+		return simulation.ReversePosition
+	default:
+		return simulation.NormalPosition
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+// Package simulationpb implements the Simulation gRPC service described by simulation.proto on
+// top of a simulation.Simulation, reusing its event hub for the streaming subscription.
+//
+// simulation.pb.go and simulation_grpc.pb.go are hand-maintained rather than produced by
+// protoc: this tree has no protoc/protoc-gen-go-grpc toolchain wired into its build. Their
+// message types are therefore plain structs with json struct tags instead of protoc-gen-go's
+// generated reflection metadata, and codec.go registers a JSON-based grpc/encoding.Codec under
+// the "proto" name - the content-subtype grpc-go clients send by default - so the service works
+// over gRPC's framing and streaming without real protobuf wire encoding. If a protoc toolchain
+// is added to the build later, these three files should be replaced by real protoc-gen-go and
+// protoc-gen-go-grpc output and codec.go removed, at which point the service starts speaking
+// the wire format simulation.proto actually describes.
+package simulationpb
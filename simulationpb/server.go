@@ -0,0 +1,170 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+package simulationpb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ts2/ts2-sim-server/simulation"
+)
+
+// AuthFunc authenticates an incoming RPC from its context, e.g. by checking a bearer token
+// carried in the gRPC metadata. It runs once per RPC, before the handler. An error rejects the
+// call with that error's gRPC status.
+type AuthFunc func(ctx context.Context) error
+
+// Server implements the generated SimulationServer interface on top of a running
+// simulation.Simulation. It is only safe to expose beyond localhost once an AuthFunc is
+// configured and the *grpc.Server is created with UnaryInterceptor and StreamInterceptor
+// below as its interceptors; calls are authenticated only through them.
+type Server struct {
+	UnimplementedSimulationServer
+
+	sim  *simulation.Simulation
+	auth AuthFunc
+}
+
+// NewServer wraps sim for gRPC access. auth may be nil, in which case every RPC is allowed -
+// appropriate only when the server is bound to localhost.
+func NewServer(sim *simulation.Simulation, auth AuthFunc) *Server {
+	return &Server{sim: sim, auth: auth}
+}
+
+// UnaryInterceptor authenticates unary RPCs via Server.auth before they reach their handler.
+func (s *Server) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.auth != nil {
+		if err := s.auth(ctx); err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor authenticates streaming RPCs via Server.auth before they reach their
+// handler. This package does not configure keepalive: it is set, if at all, with
+// grpc.KeepaliveParams/grpc.KeepaliveEnforcementPolicy on the *grpc.Server that registers this
+// Server.
+func (s *Server) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if s.auth != nil {
+		if err := s.auth(ss.Context()); err != nil {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+	}
+	return handler(srv, ss)
+}
+
+// Subscribe streams events matching req's filter until the client disconnects or the
+// underlying simulation.EventSource is closed.
+func (s *Server) Subscribe(req *FilterRequest, stream Simulation_SubscribeServer) error {
+	source, err := s.sim.Subscribe(filterFromProto(req))
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer source.Close()
+
+	for {
+		evt, err := source.Next()
+		if err == simulation.ErrSubscriptionClosed {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		pbEvt, err := eventToProto(evt)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := stream.Send(pbEvt); err != nil {
+			return err
+		}
+	}
+}
+
+// ListTrains returns the current service code, speed and status of every train.
+func (s *Server) ListTrains(ctx context.Context, _ *emptypb.Empty) (*TrainList, error) {
+	list := &TrainList{}
+	for _, t := range s.sim.Trains {
+		list.Trains = append(list.Trains, &Train{
+			ServiceCode: t.ServiceCode,
+			Speed:       t.Speed(),
+			Status:      t.Status().String(),
+		})
+	}
+	return list, nil
+}
+
+// ActivateRoute activates the route identified by req.RouteId.
+func (s *Server) ActivateRoute(ctx context.Context, req *ActivateRouteRequest) (*emptypb.Empty, error) {
+	route, ok := s.sim.Routes[int(req.RouteId)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no route %d", req.RouteId)
+	}
+	if err := route.Activate(); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// SetPointsPosition moves the points item identified by req.PointsId to req.Position.
+func (s *Server) SetPointsPosition(ctx context.Context, req *SetPointsPositionRequest) (*emptypb.Empty, error) {
+	ti, ok := s.sim.TrackItems[int(req.PointsId)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no track item %d", req.PointsId)
+	}
+	pi, ok := ti.(*simulation.PointsItem)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "track item %d is not a points item", req.PointsId)
+	}
+	pi.SetPosition(pointsPositionFromProto(req.Position))
+	return &emptypb.Empty{}, nil
+}
+
+// Pause stops the simulation's clock ticker.
+func (s *Server) Pause(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	s.sim.Pause()
+	return &emptypb.Empty{}, nil
+}
+
+// Start (re)starts the simulation's clock ticker.
+func (s *Server) Start(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	s.sim.Start()
+	return &emptypb.Empty{}, nil
+}
+
+// Snapshot returns the current mutable runtime state, as produced by Simulation.Snapshot.
+func (s *Server) Snapshot(ctx context.Context, _ *emptypb.Empty) (*SnapshotReply, error) {
+	data, err := s.sim.Snapshot()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &SnapshotReply{Data: data}, nil
+}
+
+// Restore loads a snapshot previously returned by Snapshot.
+func (s *Server) Restore(ctx context.Context, req *SnapshotReply) (*emptypb.Empty, error) {
+	if err := s.sim.LoadSnapshot(req.Data); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+// Code generated by hand from simulation.proto; DO NOT regenerate with protoc without also
+// removing codec.go. See doc.go.
+
+package simulationpb
+
+// FilterRequest mirrors simulation.EventFilter: Kinds and ObjectIds are empty-matches-all, and
+// combine with a logical AND when both are set.
+type FilterRequest struct {
+	Kinds     []string `json:"kinds,omitempty"`
+	ObjectIds []string `json:"object_ids,omitempty"`
+}
+
+// Event mirrors simulation.Event. Payload is the JSON encoding of the event's Object, so that
+// clients don't need a generated message type per event kind.
+type Event struct {
+	Kind    string `json:"kind,omitempty"`
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// TrainList carries the current state of every train, as returned by ListTrains.
+type TrainList struct {
+	Trains []*Train `json:"trains,omitempty"`
+}
+
+// Train mirrors one simulation.Train's externally visible state.
+type Train struct {
+	ServiceCode string  `json:"service_code,omitempty"`
+	Speed       float64 `json:"speed,omitempty"`
+	Status      string  `json:"status,omitempty"`
+}
+
+// ActivateRouteRequest identifies the route ActivateRoute should activate.
+type ActivateRouteRequest struct {
+	RouteId int32 `json:"route_id,omitempty"`
+}
+
+// SetPointsPositionRequest identifies the points item SetPointsPosition should move, and the
+// position ("normal" or "reverse") to move it to.
+type SetPointsPositionRequest struct {
+	PointsId int32  `json:"points_id,omitempty"`
+	Position string `json:"position,omitempty"`
+}
+
+// SnapshotReply carries the payload produced by simulation.Simulation.Snapshot, and is also
+// accepted by Restore to reload it.
+type SnapshotReply struct {
+	Data []byte `json:"data,omitempty"`
+}
@@ -0,0 +1,351 @@
+// Copyright (C) 2008-2018 by Nicolas Piganeau and the TS2 TEAM
+// (See AUTHORS file)
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the
+// Free Software Foundation, Inc.,
+// 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+
+// Code generated by hand from simulation.proto, following protoc-gen-go-grpc's output shape;
+// DO NOT regenerate with protoc without also removing codec.go. See doc.go.
+
+package simulationpb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	Simulation_Subscribe_FullMethodName         = "/simulationpb.Simulation/Subscribe"
+	Simulation_ListTrains_FullMethodName        = "/simulationpb.Simulation/ListTrains"
+	Simulation_ActivateRoute_FullMethodName     = "/simulationpb.Simulation/ActivateRoute"
+	Simulation_SetPointsPosition_FullMethodName = "/simulationpb.Simulation/SetPointsPosition"
+	Simulation_Pause_FullMethodName             = "/simulationpb.Simulation/Pause"
+	Simulation_Start_FullMethodName             = "/simulationpb.Simulation/Start"
+	Simulation_Snapshot_FullMethodName          = "/simulationpb.Simulation/Snapshot"
+	Simulation_Restore_FullMethodName           = "/simulationpb.Simulation/Restore"
+)
+
+// SimulationClient is the client API for the Simulation service.
+type SimulationClient interface {
+	Subscribe(ctx context.Context, in *FilterRequest, opts ...grpc.CallOption) (Simulation_SubscribeClient, error)
+	ListTrains(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TrainList, error)
+	ActivateRoute(ctx context.Context, in *ActivateRouteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SetPointsPosition(ctx context.Context, in *SetPointsPositionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Pause(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Start(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Snapshot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SnapshotReply, error)
+	Restore(ctx context.Context, in *SnapshotReply, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type simulationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewSimulationClient creates a client for the Simulation service on cc.
+func NewSimulationClient(cc grpc.ClientConnInterface) SimulationClient {
+	return &simulationClient{cc}
+}
+
+func (c *simulationClient) Subscribe(ctx context.Context, in *FilterRequest, opts ...grpc.CallOption) (Simulation_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Simulation_ServiceDesc.Streams[0], Simulation_Subscribe_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &simulationSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// Simulation_SubscribeClient is the stream handle returned by SimulationClient.Subscribe.
+type Simulation_SubscribeClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type simulationSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *simulationSubscribeClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *simulationClient) ListTrains(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TrainList, error) {
+	out := new(TrainList)
+	if err := c.cc.Invoke(ctx, Simulation_ListTrains_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) ActivateRoute(ctx context.Context, in *ActivateRouteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	if err := c.cc.Invoke(ctx, Simulation_ActivateRoute_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) SetPointsPosition(ctx context.Context, in *SetPointsPositionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	if err := c.cc.Invoke(ctx, Simulation_SetPointsPosition_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) Pause(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	if err := c.cc.Invoke(ctx, Simulation_Pause_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) Start(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	if err := c.cc.Invoke(ctx, Simulation_Start_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) Snapshot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SnapshotReply, error) {
+	out := new(SnapshotReply)
+	if err := c.cc.Invoke(ctx, Simulation_Snapshot_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) Restore(ctx context.Context, in *SnapshotReply, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	if err := c.cc.Invoke(ctx, Simulation_Restore_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SimulationServer is the server API for the Simulation service.
+type SimulationServer interface {
+	Subscribe(*FilterRequest, Simulation_SubscribeServer) error
+	ListTrains(context.Context, *emptypb.Empty) (*TrainList, error)
+	ActivateRoute(context.Context, *ActivateRouteRequest) (*emptypb.Empty, error)
+	SetPointsPosition(context.Context, *SetPointsPositionRequest) (*emptypb.Empty, error)
+	Pause(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	Start(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	Snapshot(context.Context, *emptypb.Empty) (*SnapshotReply, error)
+	Restore(context.Context, *SnapshotReply) (*emptypb.Empty, error)
+}
+
+// UnimplementedSimulationServer can be embedded in a SimulationServer implementation to satisfy
+// the interface before all methods are implemented, and to keep implementations source
+// compatible with future methods added to the service.
+type UnimplementedSimulationServer struct{}
+
+func (UnimplementedSimulationServer) Subscribe(*FilterRequest, Simulation_SubscribeServer) error {
+	return status.Error(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedSimulationServer) ListTrains(context.Context, *emptypb.Empty) (*TrainList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTrains not implemented")
+}
+func (UnimplementedSimulationServer) ActivateRoute(context.Context, *ActivateRouteRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method ActivateRoute not implemented")
+}
+func (UnimplementedSimulationServer) SetPointsPosition(context.Context, *SetPointsPositionRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetPointsPosition not implemented")
+}
+func (UnimplementedSimulationServer) Pause(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method Pause not implemented")
+}
+func (UnimplementedSimulationServer) Start(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method Start not implemented")
+}
+func (UnimplementedSimulationServer) Snapshot(context.Context, *emptypb.Empty) (*SnapshotReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method Snapshot not implemented")
+}
+func (UnimplementedSimulationServer) Restore(context.Context, *SnapshotReply) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method Restore not implemented")
+}
+
+// RegisterSimulationServer registers srv with s so that its RPCs are served.
+func RegisterSimulationServer(s grpc.ServiceRegistrar, srv SimulationServer) {
+	s.RegisterService(&Simulation_ServiceDesc, srv)
+}
+
+func _Simulation_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FilterRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SimulationServer).Subscribe(m, &simulationSubscribeServer{stream})
+}
+
+// Simulation_SubscribeServer is the server-side stream handle passed to SimulationServer.
+// Subscribe.
+type Simulation_SubscribeServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type simulationSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *simulationSubscribeServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Simulation_ListTrains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).ListTrains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: Simulation_ListTrains_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).ListTrains(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_ActivateRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActivateRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).ActivateRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: Simulation_ActivateRoute_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).ActivateRoute(ctx, req.(*ActivateRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_SetPointsPosition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPointsPositionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).SetPointsPosition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: Simulation_SetPointsPosition_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).SetPointsPosition(ctx, req.(*SetPointsPositionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: Simulation_Pause_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).Pause(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).Start(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: Simulation_Start_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).Start(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_Snapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).Snapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: Simulation_Snapshot_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).Snapshot(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotReply)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: Simulation_Restore_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).Restore(ctx, req.(*SnapshotReply))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Simulation_ServiceDesc is the grpc.ServiceDesc for the Simulation service, used by
+// RegisterSimulationServer.
+var Simulation_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "simulationpb.Simulation",
+	HandlerType: (*SimulationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "ListTrains", Handler: _Simulation_ListTrains_Handler},
+		{MethodName: "ActivateRoute", Handler: _Simulation_ActivateRoute_Handler},
+		{MethodName: "SetPointsPosition", Handler: _Simulation_SetPointsPosition_Handler},
+		{MethodName: "Pause", Handler: _Simulation_Pause_Handler},
+		{MethodName: "Start", Handler: _Simulation_Start_Handler},
+		{MethodName: "Snapshot", Handler: _Simulation_Snapshot_Handler},
+		{MethodName: "Restore", Handler: _Simulation_Restore_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Simulation_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "simulation.proto",
+}